@@ -0,0 +1,55 @@
+package bench
+
+import (
+	"testing"
+
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+)
+
+// runNamed replays a single (algo, size, messageBytes) point under Go's
+// benchmark timer, named after the topology, e.g.
+// BenchmarkAllReduce_Ring_16Nodes_1MB.
+func runNamed(b *testing.B, algo kb.KungFu_AllReduceAlgo, k, msgBytes int) {
+	net := newMockNetwork()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runOnce(algo, k, msgBytes, OneAtATime, net)
+	}
+}
+
+func BenchmarkAllReduce_Star_16Nodes_1MB(b *testing.B) {
+	runNamed(b, kb.KungFu_Star, 16, 1<<20)
+}
+
+func BenchmarkAllReduce_Clique_16Nodes_1MB(b *testing.B) {
+	runNamed(b, kb.KungFu_Clique, 16, 1<<20)
+}
+
+func BenchmarkAllReduce_Ring_16Nodes_1MB(b *testing.B) {
+	runNamed(b, kb.KungFu_Ring, 16, 1<<20)
+}
+
+func BenchmarkAllReduce_Tree_16Nodes_1MB(b *testing.B) {
+	runNamed(b, kb.KungFu_Tree, 16, 1<<20)
+}
+
+func TestRunMatrixProducesComparableStats(t *testing.T) {
+	report := RunMatrix(Matrix{
+		Algos:         []kb.KungFu_AllReduceAlgo{kb.KungFu_Star, kb.KungFu_Ring},
+		ClusterSizes:  []int{4, 8},
+		MessageBytes:  []int{1024, 1 << 16},
+		FetchPatterns: []FetchPattern{OneAtATime, LargeBatch},
+	})
+	want := 2 * 2 * 2 * 2
+	if len(report.Runs) != want {
+		t.Fatalf("got %d runs, want %d", len(report.Runs), want)
+	}
+	for _, r := range report.Runs {
+		if r.BytesRecv == 0 {
+			t.Errorf("%s: expected non-zero BytesRecv", r.Name())
+		}
+	}
+	if _, err := report.MarshalJSON(); err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+}
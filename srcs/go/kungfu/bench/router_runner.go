@@ -0,0 +1,86 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+	"github.com/lsds/KungFu/srcs/go/plan"
+	rch "github.com/lsds/KungFu/srcs/go/rchannel"
+)
+
+// RunMatrixOverRouter replays m's sweep the same way RunMatrix does, except
+// each simulated rank moves its messages through its own rch.Router, so the
+// numbers reflect a real network rather than in-process channels. The
+// cluster size is always len(peers): m.ClusterSizes is not used, and
+// Duplicates is left at 0. routerFor is supplied by the caller since
+// constructing a *rch.Router requires the process's real listening address.
+func RunMatrixOverRouter(m Matrix, peers []plan.PeerSpec, routerFor func(rank int) *rch.Router) Report {
+	var report Report
+	for _, algo := range m.Algos {
+		for _, sz := range m.MessageBytes {
+			for _, fp := range m.FetchPatterns {
+				report.Runs = append(report.Runs, runOnceOverRouter(algo, peers, sz, fp, routerFor))
+			}
+		}
+	}
+	return report
+}
+
+func runOnceOverRouter(algo kb.KungFu_AllReduceAlgo, peers []plan.PeerSpec, msgBytes int, fp FetchPattern, routerFor func(rank int) *rch.Router) Stats {
+	k := len(peers)
+	pairs := graphPairsFor(algo, k)
+
+	count := msgBytes / 4
+	if count <= 0 {
+		count = 1
+	}
+	chunks := 1
+	if fp == OneAtATime {
+		chunks = 4
+	}
+	perPair := count/len(pairs)/chunks + 1
+
+	var sent, recv int64
+	start := time.Now()
+	var wg sync.WaitGroup
+	for rank := 0; rank < k; rank++ {
+		wg.Add(1)
+		go func(rank int) {
+			defer wg.Done()
+			t := newRouterTransport(routerFor(rank))
+			for c := 0; c < chunks; c++ {
+				for pi, pair := range pairs {
+					name := fmt.Sprintf("bench::%d::%d", c, pi)
+					runGraphOnTransport(t, peers, rank, pair.gather, name, perPair)
+					runGraphOnTransport(t, peers, rank, pair.bcast, name, perPair)
+				}
+			}
+			atomic.AddInt64(&sent, t.BytesSent())
+			atomic.AddInt64(&recv, t.BytesRecv())
+		}(rank)
+	}
+	wg.Wait()
+
+	return Stats{
+		Algo:         algoName(algo),
+		ClusterSize:  k,
+		MessageBytes: msgBytes,
+		FetchPattern: string(fp),
+		BytesSent:    sent,
+		BytesRecv:    recv,
+		Wall:         time.Since(start),
+	}
+}
+
+func runGraphOnTransport(t transport, peers []plan.PeerSpec, rank int, g *plan.Graph, name string, count int) {
+	payload := make([]byte, count*4)
+	for _, p := range g.Prevs(rank) {
+		t.Recv(rank, peers[p], name)
+	}
+	for _, n := range g.Nexts(rank) {
+		t.Send(rank, peers[n], name, payload)
+	}
+}
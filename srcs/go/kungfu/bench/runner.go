@@ -0,0 +1,173 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// FetchPattern selects how a run pulls its input, mirroring the two shapes
+// real callers use: one small tensor at a time, or everything batched up
+// front.
+type FetchPattern string
+
+const (
+	OneAtATime FetchPattern = "one-at-a-time"
+	LargeBatch FetchPattern = "large-batch"
+)
+
+// Matrix describes the sweep a single RunMatrix call should cover.
+type Matrix struct {
+	Algos         []kb.KungFu_AllReduceAlgo
+	ClusterSizes  []int
+	MessageBytes  []int
+	FetchPatterns []FetchPattern
+}
+
+// graphPair is one strategy's (gather, bcast) graphs.
+type graphPair struct {
+	gather *plan.Graph
+	bcast  *plan.Graph
+}
+
+// graphPairsFor builds the graph pairs algo runs over a cluster of the
+// given size, mirroring session.go's createXStrategies shapes: one pair for
+// Star/Tree, k independent star pairs (one per root) for Clique, since
+// createCliqueStrategies fans the buffer out over k parallel stars rather
+// than a single one rooted at rank 0, and likewise k ring pairs (one per
+// rotation) for Ring, as createRingStrategies builds.
+func graphPairsFor(algo kb.KungFu_AllReduceAlgo, k int) []graphPair {
+	switch algo {
+	case kb.KungFu_Ring:
+		pairs := make([]graphPair, k)
+		for r := 0; r < k; r++ {
+			gather, bcast := plan.GenCircularGraphPair(k, r)
+			pairs[r] = graphPair{gather, bcast}
+		}
+		return pairs
+	case kb.KungFu_Clique:
+		pairs := make([]graphPair, k)
+		for r := 0; r < k; r++ {
+			bcastGraph := plan.GenStarBcastGraph(k, r)
+			pairs[r] = graphPair{plan.GenDefaultGatherGraph(bcastGraph), bcastGraph}
+		}
+		return pairs
+	case kb.KungFu_Tree:
+		peers := make([]plan.PeerSpec, k)
+		for i := range peers {
+			peers[i] = plan.PeerSpec{Rank: i}
+		}
+		bcastGraph := plan.GenDefaultBcastGraph(peers)
+		return []graphPair{{plan.GenDefaultGatherGraph(bcastGraph), bcastGraph}}
+	default:
+		bcastGraph := plan.GenStarBcastGraph(k, 0)
+		return []graphPair{{plan.GenDefaultGatherGraph(bcastGraph), bcastGraph}}
+	}
+}
+
+// RunMatrix drives every (algo, cluster size, message size, fetch pattern)
+// combination in m once each, using the in-process mock transport, and
+// returns one Stats entry per combination.
+func RunMatrix(m Matrix) Report {
+	var report Report
+	for _, algo := range m.Algos {
+		for _, k := range m.ClusterSizes {
+			for _, sz := range m.MessageBytes {
+				for _, fp := range m.FetchPatterns {
+					report.Runs = append(report.Runs, runOnce(algo, k, sz, fp, newMockNetwork()))
+				}
+			}
+		}
+	}
+	return report
+}
+
+func runOnce(algo kb.KungFu_AllReduceAlgo, k, msgBytes int, fp FetchPattern, net *mockNetwork) Stats {
+	pairs := graphPairsFor(algo, k)
+	peers := make([]plan.PeerSpec, k)
+	for i := range peers {
+		peers[i] = plan.PeerSpec{Rank: i}
+	}
+
+	count := msgBytes / 4 // elements of kb.KungFu_INT32
+	if count <= 0 {
+		count = 1
+	}
+
+	chunks := 1
+	if fp == OneAtATime {
+		chunks = 4
+	}
+
+	// The buffer is split evenly across pairs, the same way runStrategies
+	// partitions a Workspace across k parallel strategies for Clique and Ring.
+	perPair := count/len(pairs)/chunks + 1
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for rank := 0; rank < k; rank++ {
+		wg.Add(1)
+		go func(rank int) {
+			defer wg.Done()
+			for c := 0; c < chunks; c++ {
+				for pi, pair := range pairs {
+					name := fmt.Sprintf("bench::%d::%d", c, pi)
+					runGraphOnMock(net, peers, rank, pair.gather, name, perPair)
+					runGraphOnMock(net, peers, rank, pair.bcast, name, perPair)
+				}
+			}
+		}(rank)
+	}
+	wg.Wait()
+	wall := time.Since(start)
+
+	return Stats{
+		Algo:         algoName(algo),
+		ClusterSize:  k,
+		MessageBytes: msgBytes,
+		FetchPattern: string(fp),
+		BytesSent:    net.BytesSent(),
+		BytesRecv:    net.BytesRecv(),
+		Duplicates:   net.Duplicates(),
+		Wall:         wall,
+	}
+}
+
+// runGraphOnMock replays one graph's fan-in/fan-out for rank against net,
+// the same Prevs/Nexts traversal session.runGraphs uses, but moving plain
+// byte payloads instead of reducing real tensors.
+func runGraphOnMock(net *mockNetwork, peers []plan.PeerSpec, rank int, g *plan.Graph, name string, count int) {
+	payload := make([]byte, count*4)
+
+	prevs := g.Prevs(rank)
+	if g.IsSelfLoop(rank) {
+		var wg sync.WaitGroup
+		for _, p := range prevs {
+			wg.Add(1)
+			go func(p int) {
+				defer wg.Done()
+				if len(net.Recv(rank, peers[p], name)) > 0 && len(prevs) > 1 {
+					net.noteDuplicate()
+				}
+			}(p)
+		}
+		wg.Wait()
+	} else {
+		for _, p := range prevs {
+			net.Recv(rank, peers[p], name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, n := range g.Nexts(rank) {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			net.Send(rank, peers[n], name, payload)
+		}(n)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+)
+
+// Stats is a single benchmark run's result, shaped for offline analysis
+// (e.g. comparing strategies across cluster sizes and message sizes).
+type Stats struct {
+	Algo         string        `json:"algo"`
+	ClusterSize  int           `json:"cluster_size"`
+	MessageBytes int           `json:"message_bytes"`
+	FetchPattern string        `json:"fetch_pattern"`
+	BytesSent    int64         `json:"bytes_sent"`
+	BytesRecv    int64         `json:"bytes_recv"`
+	Duplicates   int64         `json:"duplicates"`
+	Wall         time.Duration `json:"wall_ns"`
+}
+
+// ThroughputMBps is the aggregate bytes-delivered-per-second across the
+// simulated cluster for this run.
+func (s Stats) ThroughputMBps() float64 {
+	if s.Wall <= 0 {
+		return 0
+	}
+	return float64(s.BytesRecv) / s.Wall.Seconds() / (1024 * 1024)
+}
+
+// Name renders a Go-benchmark-style identifier, e.g. "Ring-16Nodes-1048576B"
+// for a 1 MiB message.
+func (s Stats) Name() string {
+	return fmt.Sprintf("%s-%dNodes-%dB", s.Algo, s.ClusterSize, s.MessageBytes)
+}
+
+// Report is a full matrix run, ready to be written out as JSON.
+type Report struct {
+	Runs []Stats `json:"runs"`
+}
+
+func (r Report) MarshalJSON() ([]byte, error) {
+	type alias Report
+	return json.MarshalIndent(alias(r), "", "  ")
+}
+
+func algoName(a kb.KungFu_AllReduceAlgo) string {
+	switch a {
+	case kb.KungFu_Star:
+		return "Star"
+	case kb.KungFu_Clique:
+		return "Clique"
+	case kb.KungFu_Ring:
+		return "Ring"
+	case kb.KungFu_Tree:
+		return "Tree"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lsds/KungFu/srcs/go/plan"
+	rch "github.com/lsds/KungFu/srcs/go/rchannel"
+)
+
+// transport is the narrow slice of rch.Router's interface the bench runner
+// needs, so a run can be driven either over loopback TCP or over an
+// in-process mock, both counting bytes sent and received the same way.
+// Only the mock counts duplicates; routerTransport always reports 0.
+type transport interface {
+	Send(self int, peer plan.PeerSpec, name string, data []byte)
+	Recv(self int, peer plan.PeerSpec, name string) []byte
+
+	BytesSent() int64
+	BytesRecv() int64
+	Duplicates() int64
+}
+
+// mockNetwork is an in-process transport standing in for rch.Router: N
+// peers exchange messages over buffered channels keyed by (from, name)
+// instead of real sockets, so a full matrix run costs no TCP connections.
+type mockNetwork struct {
+	mu    sync.Mutex
+	boxes map[string]chan []byte
+
+	sent, recv, dup int64
+}
+
+func newMockNetwork() *mockNetwork {
+	return &mockNetwork{boxes: make(map[string]chan []byte)}
+}
+
+func (n *mockNetwork) box(from int, name string) chan []byte {
+	key := fmt.Sprintf("%d/%s", from, name)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	b, ok := n.boxes[key]
+	if !ok {
+		b = make(chan []byte, 64)
+		n.boxes[key] = b
+	}
+	return b
+}
+
+func (n *mockNetwork) Send(self int, peer plan.PeerSpec, name string, data []byte) {
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	n.box(self, name) <- cp
+	atomic.AddInt64(&n.sent, int64(len(data)))
+}
+
+func (n *mockNetwork) Recv(self int, peer plan.PeerSpec, name string) []byte {
+	data := <-n.box(peer.Rank, name)
+	atomic.AddInt64(&n.recv, int64(len(data)))
+	return data
+}
+
+// noteDuplicate accounts one message received at a gather node fan-in that
+// has more than one sender; every such message counts, the first included.
+func (n *mockNetwork) noteDuplicate() {
+	atomic.AddInt64(&n.dup, 1)
+}
+
+func (n *mockNetwork) BytesSent() int64  { return atomic.LoadInt64(&n.sent) }
+func (n *mockNetwork) BytesRecv() int64  { return atomic.LoadInt64(&n.recv) }
+func (n *mockNetwork) Duplicates() int64 { return atomic.LoadInt64(&n.dup) }
+
+// routerTransport drives a real rch.Router so the same matrix can be
+// replayed over actual TCP connections between processes.
+type routerTransport struct {
+	router *rch.Router
+
+	sent, recv, dup int64
+}
+
+func newRouterTransport(router *rch.Router) *routerTransport {
+	return &routerTransport{router: router}
+}
+
+func (t *routerTransport) Send(self int, peer plan.PeerSpec, name string, data []byte) {
+	t.router.Send(peer.NetAddr.WithName(name), data)
+	atomic.AddInt64(&t.sent, int64(len(data)))
+}
+
+func (t *routerTransport) Recv(self int, peer plan.PeerSpec, name string) []byte {
+	m := t.router.Recv(peer.NetAddr.WithName(name))
+	atomic.AddInt64(&t.recv, int64(len(m.Data)))
+	return m.Data
+}
+
+func (t *routerTransport) BytesSent() int64  { return atomic.LoadInt64(&t.sent) }
+func (t *routerTransport) BytesRecv() int64  { return atomic.LoadInt64(&t.recv) }
+func (t *routerTransport) Duplicates() int64 { return atomic.LoadInt64(&t.dup) }
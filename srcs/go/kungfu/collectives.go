@@ -0,0 +1,137 @@
+package kungfu
+
+import (
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// ScatterWorkspace is a Workspace whose ranks each own a different slice of
+// the buffer rather than the whole thing, as required by ReduceScatter,
+// AllGather and Scan. Displs and Counts are per-rank, in elements, indexed
+// by rank: Displs[i] is where rank i's slice starts in SendBuf/RecvBuf, and
+// Counts[i] is how many elements it owns.
+type ScatterWorkspace struct {
+	Workspace
+	Displs []int
+	Counts []int
+}
+
+// ReduceScatter reduces w.SendBuf across all peers and leaves each rank
+// holding only its own slice of the result, as given by
+// w.Displs[myRank]/w.Counts[myRank]. Unlike AllReduce, no rank ever holds
+// more than one chunk's worth of data on the wire at a time: it runs the
+// classic ring reduce-scatter, circulating one Counts[i]-sized chunk per
+// hop over k-1 steps rather than reducing the whole buffer and slicing the
+// answer out locally.
+func (sess *session) ReduceScatter(w ScatterWorkspace) int {
+	k := sess.cluster.Size()
+	myRank := sess.cluster.MyRank()
+	if k == 1 {
+		w.RecvBuf.CopyFrom(w.SendBuf.Slice(w.Displs[0], w.Counts[0]))
+		return 0
+	}
+	next := sess.cluster.GetPeer((myRank + 1) % k)
+	prev := sess.cluster.GetPeer((myRank - 1 + k) % k)
+
+	// own[i] is this rank's running copy of chunk i, seeded from its own
+	// unreduced contribution. Every chunk is forwarded on its way to the
+	// rank that owns it; only own[myRank] survives the loop.
+	own := make([]*kb.Buffer, k)
+	for i := 0; i < k; i++ {
+		own[i] = kb.NewBuffer(w.Counts[i], w.SendBuf.Type)
+		own[i].CopyFrom(w.SendBuf.Slice(w.Displs[i], w.Counts[i]))
+	}
+
+	sendIdx := (myRank - 1 + k) % k
+	for step := 0; step < k-1; step++ {
+		recvIdx := (sendIdx - 1 + k) % k
+		sess.router.Send(next.NetAddr.WithName(w.Name), own[sendIdx].Data)
+		m := sess.router.Recv(prev.NetAddr.WithName(w.Name))
+		b := &kb.Buffer{Data: m.Data, Count: w.Counts[recvIdx], Type: w.SendBuf.Type}
+		kb.Transform(own[recvIdx], b, w.OP)
+		sendIdx = recvIdx
+	}
+	w.RecvBuf.CopyFrom(own[myRank])
+	return 0
+}
+
+// AllGather places w.SendBuf (this rank's w.Counts[myRank]-sized slice) into
+// w.RecvBuf at every rank's Displs offset. Like ReduceScatter, it runs a
+// ring: each rank's chunk is forwarded around the ring once per other rank,
+// so a single Counts[i]-sized payload moves per hop instead of a full-size
+// buffer.
+func (sess *session) AllGather(w ScatterWorkspace) int {
+	k := sess.cluster.Size()
+	myRank := sess.cluster.MyRank()
+	if k == 1 {
+		w.RecvBuf.Slice(w.Displs[0], w.Counts[0]).CopyFrom(w.SendBuf)
+		return 0
+	}
+	next := sess.cluster.GetPeer((myRank + 1) % k)
+	prev := sess.cluster.GetPeer((myRank - 1 + k) % k)
+
+	w.RecvBuf.Slice(w.Displs[myRank], w.Counts[myRank]).CopyFrom(w.SendBuf)
+
+	sendIdx := myRank
+	for step := 0; step < k-1; step++ {
+		recvIdx := (sendIdx - 1 + k) % k
+		chunk := w.RecvBuf.Slice(w.Displs[sendIdx], w.Counts[sendIdx])
+		sess.router.Send(next.NetAddr.WithName(w.Name), chunk.Data)
+		m := sess.router.Recv(prev.NetAddr.WithName(w.Name))
+		b := &kb.Buffer{Data: m.Data, Count: w.Counts[recvIdx], Type: w.SendBuf.Type}
+		w.RecvBuf.Slice(w.Displs[recvIdx], w.Counts[recvIdx]).CopyFrom(b)
+		sendIdx = recvIdx
+	}
+	return 0
+}
+
+// Scan computes an inclusive prefix reduction of w across ranks: rank i's
+// result folds in ranks 0..i, in rank order. It always uses
+// kb.KungFu_PREFIX_SUM regardless of w.OP, since Scan's causal ordering
+// only makes sense for a running total.
+func (sess *session) Scan(w ScatterWorkspace) int {
+	w.OP = kb.KungFu_PREFIX_SUM
+	graphs := plan.GenScanGraphs(sess.cluster.Peers)
+	return code(sess.runScanGraphs(w.Workspace, graphs))
+}
+
+// runScanGraphs drives Scan's graphs directly instead of going through
+// runGraphs: runGraphs only ever folds an incoming value into the local
+// buffer via Transform/Transform2 when plan.Graph.IsSelfLoop marks a node as
+// the strategy's single gather root, everywhere else it just relays
+// (recvInto is a bare CopyFrom). Scan's graphs have no such root — every
+// rank with a prev edge needs to fold that edge's value into its own
+// running total and hand the sum on, not relay what it was given.
+func (sess *session) runScanGraphs(w Workspace, graphs []*plan.Graph) error {
+	if sess.cluster.Size() == 1 {
+		w.RecvBuf.CopyFrom(w.SendBuf)
+		return nil
+	}
+	myRank := sess.cluster.MyRank()
+	have := false
+	for _, g := range graphs {
+		for _, p := range g.Prevs(myRank) {
+			peer := sess.cluster.GetPeer(p)
+			m := sess.router.Recv(peer.NetAddr.WithName(w.Name))
+			b := &kb.Buffer{Data: m.Data, Count: w.SendBuf.Count, Type: w.SendBuf.Type}
+			if !have {
+				kb.Transform2(w.RecvBuf, w.SendBuf, b, w.OP)
+				have = true
+			} else {
+				kb.Transform(w.RecvBuf, b, w.OP)
+			}
+		}
+		for _, n := range g.Nexts(myRank) {
+			peer := sess.cluster.GetPeer(n)
+			if have {
+				sess.router.Send(peer.NetAddr.WithName(w.Name), w.RecvBuf.Data)
+			} else {
+				sess.router.Send(peer.NetAddr.WithName(w.Name), w.SendBuf.Data)
+			}
+		}
+	}
+	if !have {
+		w.RecvBuf.CopyFrom(w.SendBuf)
+	}
+	return nil
+}
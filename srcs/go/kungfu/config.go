@@ -0,0 +1,42 @@
+package kungfu
+
+import (
+	kb "github.com/lsds/KungFu/srcs/go/kungfubase"
+)
+
+// Config controls how a session is constructed and how it behaves under
+// partial failure.
+type Config struct {
+	Algo kb.KungFu_AllReduceAlgo
+
+	FaultTolerance FaultTolerance
+
+	// PipelineChunkSize is the number of micro-chunks AllReducePipelined
+	// splits a Workspace into. <= 1 disables pipelining: the whole buffer
+	// is sent as a single chunk.
+	PipelineChunkSize int
+}
+
+// FaultTolerance controls how a session reacts to peers that stop
+// responding. It is disabled (MaxPeerErrors == 0) by default so existing
+// deployments keep today's fail-fast behaviour unless they opt in.
+type FaultTolerance struct {
+	// MaxPeerErrors is the number of consecutive send/recv errors tolerated
+	// for a given peer before it is marked unhealthy. 0 disables fault
+	// tolerance.
+	MaxPeerErrors int
+
+	// CoolDown is the number of consecutive successful exchanges a
+	// previously unhealthy peer must go without error before it is
+	// re-included.
+	CoolDown int
+
+	// MinClusterSize is the smallest healthy cluster size the session will
+	// fail over to. Once HealthyRanks() would drop below this, the session
+	// stops excluding peers and returns the underlying error instead.
+	MinClusterSize int
+}
+
+func (ft FaultTolerance) enabled() bool {
+	return ft.MaxPeerErrors > 0
+}
@@ -0,0 +1,66 @@
+package kungfu
+
+import (
+	"encoding/binary"
+
+	"github.com/lsds/KungFu/srcs/go/log"
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// exclusionGossipName is the router name used for exclusion announcements,
+// kept distinct from any Workspace.Name a caller might pick for a collective.
+const exclusionGossipName = "kungfu::health::exclude"
+
+// startExclusionGossip spawns one listener per peer so that a peer this
+// rank marks unhealthy on its own is announced to every other peer, and a
+// peer another rank marks unhealthy is learned here. peerHealth.recordError
+// is otherwise purely local: without gossip, rank A can regenerate and
+// retry over a shrunk, translated graph that excludes peer C while ranks B
+// and D still run the original topology, and they will disagree about who
+// sends to whom. Gossip doesn't make the cluster's view of health
+// atomically consistent, but it converges every rank's HealthyRanks() onto
+// the same exclusions far faster than waiting for each rank to independently
+// cross its own MaxPeerErrors threshold against peer C.
+func (sess *session) startExclusionGossip() {
+	if !sess.config.FaultTolerance.enabled() {
+		return
+	}
+	self := sess.cluster.MyRank()
+	for _, p := range sess.cluster.Peers {
+		if p.Rank == self {
+			continue
+		}
+		go sess.listenForExclusion(p)
+	}
+}
+
+func (sess *session) listenForExclusion(peer plan.PeerSpec) {
+	for {
+		m := sess.router.Recv(peer.NetAddr.WithName(exclusionGossipName))
+		if len(m.Data) < 4 {
+			continue
+		}
+		rank := int(binary.LittleEndian.Uint32(m.Data))
+		sess.health.markUnhealthy(rank)
+		log.Warnf("peer %d reported unhealthy by peer %d", rank, peer.Rank)
+	}
+}
+
+// broadcastExclusion tells every peer other than self and rank that rank
+// just crossed MaxPeerErrors. It is best-effort: a peer that is itself
+// unreachable simply never receives it, which is fine, since an unreachable
+// peer can't participate in the next collective's strategies either way.
+func (sess *session) broadcastExclusion(rank int) {
+	self := sess.cluster.MyRank()
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(rank))
+	for _, p := range sess.cluster.Peers {
+		if p.Rank == self || p.Rank == rank {
+			continue
+		}
+		go func(p plan.PeerSpec) {
+			defer func() { recover() }()
+			sess.router.Send(p.NetAddr.WithName(exclusionGossipName), buf)
+		}(p)
+	}
+}
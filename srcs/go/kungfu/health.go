@@ -0,0 +1,130 @@
+package kungfu
+
+import (
+	"sync"
+
+	"github.com/lsds/KungFu/srcs/go/plan"
+	rch "github.com/lsds/KungFu/srcs/go/rchannel"
+)
+
+// peerHealth is the session-side view of cluster health. It consults a
+// rch.PeerHealthTracker for raw error counts and turns those into a set of
+// excluded ranks once MaxPeerErrors is crossed, re-including a rank once it
+// has gone CoolDown consecutive successful exchanges without error.
+type peerHealth struct {
+	mu        sync.RWMutex
+	tracker   *rch.PeerHealthTracker
+	threshold int
+	coolDown  int
+	unhealthy map[int]bool
+	streak    map[int]int
+}
+
+func newPeerHealth(ft FaultTolerance) *peerHealth {
+	return &peerHealth{
+		tracker:   rch.NewPeerHealthTracker(),
+		threshold: ft.MaxPeerErrors,
+		coolDown:  ft.CoolDown,
+		unhealthy: make(map[int]bool),
+		streak:    make(map[int]int),
+	}
+}
+
+// recordError accounts a failed exchange with peer and returns true if this
+// error caused the peer to cross the unhealthy threshold.
+func (h *peerHealth) recordError(rank int, addr plan.NetAddr) bool {
+	if h.threshold <= 0 {
+		return false
+	}
+	count := h.tracker.Incr(addr)
+	h.mu.Lock()
+	h.streak[rank] = 0
+	h.mu.Unlock()
+	if count < h.threshold {
+		return false
+	}
+	h.mu.Lock()
+	already := h.unhealthy[rank]
+	h.unhealthy[rank] = true
+	h.mu.Unlock()
+	return !already
+}
+
+// recordSuccess accounts a clean exchange with peer. A healthy peer's error
+// count is reset, so only consecutive errors count towards MaxPeerErrors.
+// Once an unhealthy peer has strung together CoolDown successes in a row, it
+// is re-included and its error count reset. Returns true when this call is
+// what re-included it.
+func (h *peerHealth) recordSuccess(rank int, addr plan.NetAddr) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.unhealthy[rank] {
+		h.tracker.Reset(addr)
+		return false
+	}
+	if h.coolDown <= 0 {
+		return false
+	}
+	h.streak[rank]++
+	if h.streak[rank] < h.coolDown {
+		return false
+	}
+	delete(h.unhealthy, rank)
+	delete(h.streak, rank)
+	h.tracker.Reset(addr)
+	return true
+}
+
+// markUnhealthy excludes rank unconditionally, bypassing the local error
+// threshold: it is how a rank learns of an exclusion another rank already
+// decided on its own, via sess.listenForExclusion, rather than from its own
+// observed errors.
+func (h *peerHealth) markUnhealthy(rank int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unhealthy[rank] = true
+	h.streak[rank] = 0
+}
+
+// isHealthy reports whether rank is currently excluded from strategies.
+func (h *peerHealth) isHealthy(rank int) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return !h.unhealthy[rank]
+}
+
+// excludedCount returns how many ranks are currently marked unhealthy.
+func (h *peerHealth) excludedCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.unhealthy)
+}
+
+// Peers returns the full peer list the session was created with, including
+// any peer currently marked unhealthy.
+func (sess *session) Peers() []plan.PeerSpec {
+	return sess.cluster.Peers
+}
+
+// HealthyRanks returns the ranks of peers that have not crossed
+// Config.FaultTolerance.MaxPeerErrors.
+func (sess *session) HealthyRanks() []int {
+	var ranks []int
+	for _, p := range sess.cluster.Peers {
+		if sess.health.isHealthy(p.Rank) {
+			ranks = append(ranks, p.Rank)
+		}
+	}
+	return ranks
+}
+
+// healthyPeers returns the PeerSpecs of ranks in HealthyRanks().
+func (sess *session) healthyPeers() []plan.PeerSpec {
+	var peers []plan.PeerSpec
+	for _, p := range sess.cluster.Peers {
+		if sess.health.isHealthy(p.Rank) {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
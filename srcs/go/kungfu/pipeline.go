@@ -0,0 +1,88 @@
+package kungfu
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lsds/KungFu/srcs/go/log"
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// chunkGraphs is one micro-chunk's (reduce-scatter, all-gather) graph pair,
+// tagged with the chunk index it belongs to.
+type chunkGraphs struct {
+	chunkIdx int
+	gather   *plan.Graph
+	bcast    *plan.Graph
+}
+
+// streamingStrategy yields a cluster's ring graphs one chunk at a time over
+// a channel instead of a pre-built slice. runGraphsStreaming consumes the
+// channel directly, so chunk i+1 can be dispatched as soon as it is
+// generated instead of waiting for every chunk's graphs to be built up
+// front.
+type streamingStrategy struct {
+	out chan chunkGraphs
+}
+
+// newRingStreamingStrategy builds a fresh ring (gather, bcast) pair per
+// chunk, rotating the pivot rank the same way createRingStrategies varies r
+// across its k strategies. Reusing a single rotation for every chunk would
+// make every chunk's reduction converge on and fan out from the same peer
+// concurrently, recreating the single-node bottleneck the ring algorithm
+// exists to avoid.
+func newRingStreamingStrategy(k, chunks int) *streamingStrategy {
+	s := &streamingStrategy{out: make(chan chunkGraphs)}
+	go func() {
+		defer close(s.out)
+		for c := 0; c < chunks; c++ {
+			gather, bcast := plan.GenCircularGraphPair(k, c%k)
+			s.out <- chunkGraphs{chunkIdx: c, gather: gather, bcast: bcast}
+		}
+	}()
+	return s
+}
+
+// AllReducePipelined runs a ring AllReduce over w split into
+// Config.PipelineChunkSize micro-chunks, overlapping chunk i+1's
+// reduce-scatter with chunk i's all-gather. It always uses Ring regardless
+// of Config.Algo, since the ring is what makes the pipelining worthwhile.
+func (sess *session) AllReducePipelined(w Workspace) int {
+	chunks := sess.config.PipelineChunkSize
+	if chunks <= 0 {
+		chunks = 1
+	}
+	k := sess.cluster.Size()
+	strategy := newRingStreamingStrategy(k, chunks)
+	return code(sess.runGraphsStreaming(w, chunks, strategy.out))
+}
+
+// runGraphsStreaming consumes chunkGraphs as they arrive and fans each one
+// out to runGraphs on its own goroutine and its own chunk-qualified name on
+// sess.router, so that concurrent chunks' messages never collide and a slow
+// chunk never blocks the next one from starting.
+func (sess *session) runGraphsStreaming(w Workspace, chunks int, in <-chan chunkGraphs) error {
+	parts := w.split(plan.EvenPartition, chunks)
+
+	var wg sync.WaitGroup
+	var failed int32
+	for cg := range in {
+		wg.Add(1)
+		go func(cg chunkGraphs) {
+			defer wg.Done()
+			cw := parts[cg.chunkIdx]
+			cw.Name = fmt.Sprintf("%s::chunk%d", w.Name, cg.chunkIdx)
+			if err := sess.runGraphs(cw, cg.gather, cg.bcast); err != nil {
+				log.Warnf("pipeline chunk %d failed: %v", cg.chunkIdx, err)
+				atomic.AddInt32(&failed, 1)
+			}
+		}(cg)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d pipeline chunks failed", failed, chunks)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package kungfu
+
+import (
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// rankTranslation maps the dense 0..len(peers)-1 local vertex space that
+// createStarStrategies/createRingStrategies/etc. generate graphs over back
+// onto the sparse global ranks of a shrunk, post-failover peer list.
+// Without it, a regenerated graph's vertex ids would no longer line up with
+// sess.cluster.GetPeer/MyRank, which always resolve against the original,
+// un-shrunk cluster.
+type rankTranslation struct {
+	localToGlobal []int
+}
+
+func newRankTranslation(peers []plan.PeerSpec) rankTranslation {
+	t := rankTranslation{localToGlobal: make([]int, len(peers))}
+	for i, p := range peers {
+		t.localToGlobal[i] = p.Rank
+	}
+	return t
+}
+
+// translate rebuilds g in the global rank space of a cluster of size total,
+// by replaying each local edge (prev -> i) as (localToGlobal[prev] ->
+// localToGlobal[i]).
+func (t rankTranslation) translate(g *plan.Graph, total int) *plan.Graph {
+	out := plan.NewGraph(total)
+	for local, global := range t.localToGlobal {
+		for _, prevLocal := range g.Prevs(local) {
+			out.AddEdge(t.localToGlobal[prevLocal], global)
+		}
+	}
+	return out
+}
+
+// translateStrategies translates every graph of every strategy in ss.
+func (t rankTranslation) translateStrategies(ss []strategy, total int) []strategy {
+	out := make([]strategy, len(ss))
+	for i, s := range ss {
+		graphs := make([]*plan.Graph, len(s.Graphs))
+		for j, g := range s.Graphs {
+			graphs[j] = t.translate(g, total)
+		}
+		out[i] = strategy{Graphs: graphs}
+	}
+	return out
+}
@@ -21,6 +21,8 @@ type session struct {
 	strategies []strategy
 	cluster    *plan.ProcSpec
 	router     *rch.Router
+	config     Config
+	health     *peerHealth
 }
 
 type partitionStrategy func([]plan.PeerSpec) []strategy
@@ -38,11 +40,15 @@ func newSession(c Config, ps *plan.ProcSpec, router *rch.Router) *session {
 		log.Warnf("%s is not implemeted, fallback to %s", c.Algo, kb.KungFu_Star)
 		f = createStarStrategies
 	}
-	return &session{
+	sess := &session{
 		strategies: f(ps.Peers),
 		cluster:    ps,
 		router:     router,
+		config:     c,
+		health:     newPeerHealth(c.FaultTolerance),
 	}
+	sess.startExclusionGossip()
+	return sess
 }
 
 func createStarStrategies(peers []plan.PeerSpec) []strategy {
@@ -117,15 +123,57 @@ func (sess *session) AllReduce(w Workspace) int {
 }
 
 func (sess *session) Reduce(w Workspace) int {
-	strategy := sess.strategies[0] // Assuming len(sess.strategies) > 0
-	g := strategy.Graphs[0]        // Assuming the first graph is a Gather Graph
-	return code(sess.runGraphs(w, g))
+	return code(sess.runGraphWithFailover(w, 0)) // Assuming the first graph is a Gather Graph
 }
 
 func (sess *session) Broadcast(w Workspace) int {
-	strategy := sess.strategies[0] // Assuming len(sess.strategies) > 0
-	g := strategy.Graphs[1]        // Assuming the second graph is a Broadcast Graph
-	return code(sess.runGraphs(w, g))
+	return code(sess.runGraphWithFailover(w, 1)) // Assuming the second graph is a Broadcast Graph
+}
+
+// runGraphWithFailover runs sess.strategies[0].Graphs[graphIdx], the same
+// single graph Reduce/Broadcast have always used, and gives it the same
+// failover treatment AllReduce's runStrategies does: on error, if
+// Config.FaultTolerance is enabled, regenerate strategies over the
+// currently healthy peers and retry once with that strategy's graph at the
+// same index.
+func (sess *session) runGraphWithFailover(w Workspace, graphIdx int) error {
+	g := sess.strategies[0].Graphs[graphIdx] // Assuming len(sess.strategies) > 0
+	if err := sess.runGraphs(w, g); err != nil {
+		if !sess.config.FaultTolerance.enabled() {
+			return err
+		}
+		fallback, ferr := sess.failoverStrategies()
+		if ferr != nil {
+			return fmt.Errorf("%v (fallback unavailable: %v)", err, ferr)
+		}
+		log.Warnf("retrying with %d healthy peer(s) after: %v", len(sess.HealthyRanks()), err)
+		return sess.runGraphs(w, fallback[0].Graphs[graphIdx])
+	}
+	return nil
+}
+
+// peerOpError reports that op against peer failed, so the caller can feed it
+// back into sess.health and decide whether to fail over.
+type peerOpError struct {
+	peer plan.PeerSpec
+	err  error
+}
+
+func (e *peerOpError) Error() string {
+	return fmt.Sprintf("peer %d: %v", e.peer.Rank, e.err)
+}
+
+// guard turns a panicking send/recv (the way rch.Router reports a dead
+// connection) into a *peerOpError so runGraphs can keep the rest of the
+// fan-out alive instead of taking the whole process down.
+func guard(peer plan.PeerSpec, op func(plan.PeerSpec)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &peerOpError{peer: peer, err: fmt.Errorf("%v", r)}
+		}
+	}()
+	op(peer)
+	return nil
 }
 
 func (sess *session) runGraphs(w Workspace, graphs ...*plan.Graph) error {
@@ -164,13 +212,36 @@ func (sess *session) runGraphs(w Workspace, graphs ...*plan.Graph) error {
 		recvCount++
 	}
 
+	var errs []error
+	var errLock sync.Mutex
+	recordErr := func(err error) {
+		if err == nil {
+			return
+		}
+		if pe, ok := err.(*peerOpError); ok {
+			sess.onPeerError(pe.peer)
+		}
+		errLock.Lock()
+		errs = append(errs, err)
+		errLock.Unlock()
+	}
+
+	attempt := func(rank int, op func(plan.PeerSpec)) {
+		peer := sess.cluster.GetPeer(rank)
+		if err := guard(peer, op); err != nil {
+			recordErr(err)
+		} else {
+			sess.onPeerSuccess(peer)
+		}
+	}
+
 	par := func(ranks []int, op func(plan.PeerSpec)) {
 		var wg sync.WaitGroup
 		for _, rank := range ranks {
 			wg.Add(1)
 			go func(rank int) {
-				op(sess.cluster.GetPeer(rank))
-				wg.Done()
+				defer wg.Done()
+				attempt(rank, op)
 			}(rank)
 		}
 		wg.Wait()
@@ -178,7 +249,7 @@ func (sess *session) runGraphs(w Workspace, graphs ...*plan.Graph) error {
 
 	seq := func(ranks []int, op func(plan.PeerSpec)) {
 		for _, rank := range ranks {
-			op(sess.cluster.GetPeer(rank))
+			attempt(rank, op)
 		}
 	}
 
@@ -199,10 +270,49 @@ func (sess *session) runGraphs(w Workspace, graphs ...*plan.Graph) error {
 		}
 		par(g.Nexts(myRank), sendTo)
 	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
 	return nil
 }
 
+// onPeerError feeds a failed exchange with peer into sess.health, logging
+// and gossiping the exclusion to every other peer when it is the error that
+// crosses Config.FaultTolerance.MaxPeerErrors, so the rest of the cluster
+// converges on the same healthy set instead of each rank only ever acting
+// on errors it personally observed.
+func (sess *session) onPeerError(peer plan.PeerSpec) {
+	if sess.health.recordError(peer.Rank, peer.NetAddr) {
+		log.Warnf("peer %d crossed MaxPeerErrors, marking unhealthy", peer.Rank)
+		sess.broadcastExclusion(peer.Rank)
+	}
+}
+
+// onPeerSuccess feeds a clean exchange with peer into sess.health, logging
+// when it is the success that ends peer's Config.FaultTolerance.CoolDown
+// and re-includes it in future strategies.
+func (sess *session) onPeerSuccess(peer plan.PeerSpec) {
+	if sess.health.recordSuccess(peer.Rank, peer.NetAddr) {
+		log.Warnf("peer %d completed cool-down, marking healthy again", peer.Rank)
+	}
+}
+
 func (sess *session) runStrategies(w Workspace, p partitionFunc, strategies []strategy) error {
+	if err := sess.runStrategiesOnce(w, p, strategies); err != nil {
+		if !sess.config.FaultTolerance.enabled() {
+			return err
+		}
+		fallback, ferr := sess.failoverStrategies()
+		if ferr != nil {
+			return fmt.Errorf("%v (fallback unavailable: %v)", err, ferr)
+		}
+		log.Warnf("retrying with %d healthy peer(s) after: %v", len(sess.HealthyRanks()), err)
+		return sess.runStrategiesOnce(w, p, fallback)
+	}
+	return nil
+}
+
+func (sess *session) runStrategiesOnce(w Workspace, p partitionFunc, strategies []strategy) error {
 	var wg sync.WaitGroup
 	var failed int32
 	for i, w := range w.split(p, len(strategies)) {
@@ -222,10 +332,43 @@ func (sess *session) runStrategies(w Workspace, p partitionFunc, strategies []st
 	return nil
 }
 
+// failoverStrategies regenerates strategies over the currently healthy peers
+// using the configured partitionStrategy, falling back to Star (the
+// cheapest topology to keep correct under further loss) if the configured
+// one can't be produced over a shrunk cluster, and refuses to shrink below
+// Config.FaultTolerance.MinClusterSize.
+//
+// createStarStrategies/createRingStrategies/etc. generate graphs over a
+// dense 0..len(healthy)-1 vertex space, but runGraphs resolves vertices
+// against the original, un-shrunk sess.cluster. rankTranslation rewrites
+// the regenerated graphs back into the original global rank space so a
+// peer's real rank always lines up with its vertex id, even when the
+// excluded peer isn't the highest-ranked one.
+func (sess *session) failoverStrategies() ([]strategy, error) {
+	healthy := sess.healthyPeers()
+	min := sess.config.FaultTolerance.MinClusterSize
+	if min > 0 && len(healthy) < min {
+		return nil, fmt.Errorf("healthy cluster size %d below MinClusterSize %d", len(healthy), min)
+	}
+	total := len(sess.cluster.Peers)
+	if len(healthy) == total {
+		return nil, fmt.Errorf("no unhealthy peer to exclude")
+	}
+	f := partitionStrategies[sess.config.Algo]
+	if f == nil {
+		f = createStarStrategies
+	}
+	t := newRankTranslation(healthy)
+	if ss := f(healthy); len(ss) > 0 {
+		return t.translateStrategies(ss, total), nil
+	}
+	return t.translateStrategies(createStarStrategies(healthy), total), nil
+}
+
 func code(err error) int {
 	if err == nil {
 		return 0
 	}
 	// TODO: https://www.open-mpi.org/doc/v3.1/man3/MPI.3.php#sect4
 	return 1
-}
\ No newline at end of file
+}
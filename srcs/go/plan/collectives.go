@@ -0,0 +1,57 @@
+package plan
+
+// GenScanGraphs returns the sequence of graphs an inclusive prefix-sum
+// (Scan) runs through: recursive-halving/doubling for power-of-two cluster
+// sizes (an up-sweep of log2(k) graphs with a doubling stride, then a
+// down-sweep with a halving stride), falling back to a single
+// nearest-neighbour bucket chain otherwise.
+func GenScanGraphs(peers []PeerSpec) []*Graph {
+	k := len(peers)
+	if isPowerOfTwo(k) {
+		return genRecursiveDoublingScanGraphs(k)
+	}
+	return []*Graph{genBucketScanGraph(k)}
+}
+
+func isPowerOfTwo(k int) bool {
+	return k > 0 && k&(k-1) == 0
+}
+
+// genRecursiveDoublingScanGraphs builds an up-sweep of log2(k) graphs with
+// a doubling stride dist = 1, 2, 4, ..., where rank i with (i+1) % (2*dist)
+// == 0 folds in rank i-dist's running total, followed by a down-sweep with
+// a halving stride dist = k/4, ..., 1, where such a rank i hands its total
+// on to rank i+dist. No rank both receives and sends within one graph, so
+// every rank folds in a value from before that graph even though a rank
+// receives before it sends. Edges only run from lower ranks to higher ones.
+// After both sweeps rank i has accumulated contributions from every rank <= i.
+func genRecursiveDoublingScanGraphs(k int) []*Graph {
+	var graphs []*Graph
+	dist := 1
+	for ; dist < k; dist <<= 1 {
+		g := NewGraph(k)
+		for i := 2*dist - 1; i < k; i += 2 * dist {
+			g.AddEdge(i-dist, i)
+		}
+		graphs = append(graphs, g)
+	}
+	for dist >>= 2; dist >= 1; dist >>= 1 {
+		g := NewGraph(k)
+		for i := 2*dist - 1; i+dist < k; i += 2 * dist {
+			g.AddEdge(i, i+dist)
+		}
+		graphs = append(graphs, g)
+	}
+	return graphs
+}
+
+// genBucketScanGraph chains rank i-1 -> rank i for every rank, the bucket
+// algorithm: each rank adds its own value to the running total handed to it
+// by its predecessor and forwards the result to its successor.
+func genBucketScanGraph(k int) *Graph {
+	g := NewGraph(k)
+	for i := 1; i < k; i++ {
+		g.AddEdge(i-1, i)
+	}
+	return g
+}
@@ -0,0 +1,45 @@
+package plan
+
+import "testing"
+
+func peerList(k int) []PeerSpec {
+	peers := make([]PeerSpec, k)
+	for i := range peers {
+		peers[i] = PeerSpec{Rank: i}
+	}
+	return peers
+}
+
+// TestScanGraphsProduceInclusivePrefixSums replays GenScanGraphs' edges with
+// plain ints instead of a real Workspace: rank i starts holding i+1, and for
+// every graph in order, any rank with an incoming edge folds the sender's
+// current running total into its own before the next graph runs. This is
+// the same fold-in-or-relay shape a real Scan drives hop by hop, and it
+// would have caught the chunk0-4 bug where every rank downstream of rank 0
+// just relayed rank 0's value instead of adding its own.
+func TestScanGraphsProduceInclusivePrefixSums(t *testing.T) {
+	for _, k := range []int{1, 2, 3, 4, 5, 8, 9} {
+		graphs := GenScanGraphs(peerList(k))
+		values := make([]int, k)
+		for i := range values {
+			values[i] = i + 1
+		}
+		// Edges only ever run from a lower rank to a higher one, so a
+		// single ascending pass over a graph's ranks sees each prev's
+		// contribution already folded in, the same order a real chain of
+		// network sends would enforce.
+		for _, g := range graphs {
+			for i := 0; i < k; i++ {
+				for _, p := range g.Prevs(i) {
+					values[i] += values[p]
+				}
+			}
+		}
+		for i := 0; i < k; i++ {
+			want := (i + 1) * (i + 2) / 2
+			if values[i] != want {
+				t.Errorf("k=%d: rank %d got %d, want %d", k, i, values[i], want)
+			}
+		}
+	}
+}
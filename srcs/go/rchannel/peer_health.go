@@ -0,0 +1,48 @@
+package rchannel
+
+import (
+	"sync"
+
+	"github.com/lsds/KungFu/srcs/go/plan"
+)
+
+// PeerHealthTracker tracks consecutive send/receive errors observed for each
+// peer, keyed by its NetAddr. It is deliberately a plain counter with no
+// notion of strategies or graphs, so kungfu.session can layer its own
+// threshold/cool-down policy (peerHealth) on top without rchannel needing to
+// know anything about how peers are used.
+type PeerHealthTracker struct {
+	mu     sync.Mutex
+	errors map[string]int
+}
+
+// NewPeerHealthTracker creates an empty tracker.
+func NewPeerHealthTracker() *PeerHealthTracker {
+	return &PeerHealthTracker{
+		errors: make(map[string]int),
+	}
+}
+
+// Incr records a failed send/recv against addr and returns the updated count.
+func (t *PeerHealthTracker) Incr(addr plan.NetAddr) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := addr.String()
+	t.errors[key]++
+	return t.errors[key]
+}
+
+// Reset clears the error count for addr, e.g. after a cool-down period or a
+// successful round-trip.
+func (t *PeerHealthTracker) Reset(addr plan.NetAddr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.errors, addr.String())
+}
+
+// Count returns the current error count for addr.
+func (t *PeerHealthTracker) Count(addr plan.NetAddr) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.errors[addr.String()]
+}